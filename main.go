@@ -1,14 +1,66 @@
 package main
 
 import (
-	"log"
+	"encoding/json"
+	"fmt"
 	"os"
+	"strings"
+	"sync"
 
-	"github.com/infobloxopen/auto-semver-tag/pkg/git"
 	"github.com/spf13/cobra"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infobloxopen/auto-semver-tag/pkg/git"
+	"github.com/infobloxopen/auto-semver-tag/pkg/git/gitea"
+	"github.com/infobloxopen/auto-semver-tag/pkg/git/gitlab"
 )
 
+// redactHook scrubs known secret values, such as the tokens read from the
+// environment, from every log entry's message and string-valued fields;
+// fields of any other type are left as they are.
+type redactHook struct {
+	mu      sync.Mutex
+	secrets []string
+}
+
+func (h *redactHook) add(secret string) {
+	if secret == "" {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.secrets = append(h.secrets, secret)
+}
+
+func (h *redactHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (h *redactHook) Fire(entry *log.Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, secret := range h.secrets {
+		entry.Message = strings.ReplaceAll(entry.Message, secret, "***REDACTED***")
+
+		for field, value := range entry.Data {
+			if s, ok := value.(string); ok {
+				entry.Data[field] = strings.ReplaceAll(s, secret, "***REDACTED***")
+			}
+		}
+	}
+
+	return nil
+}
+
+var secrets = &redactHook{}
+
 func main() {
+	log.AddHook(secrets)
+
 	rootCmd := &cobra.Command{
 		Use: "auto-semver-tag",
 	}
@@ -22,11 +74,71 @@ func main() {
 }
 
 func command() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:  "exec [REPOSITORY] [RELEASE_BRANCH] [COMMIT_SHA] [GH_EVENT_PATH]",
 		Args: cobra.ExactArgs(4),
 		Run:  executeCommand,
 	}
+
+	cmd.Flags().String("source", string(git.IncrementSourceLabels),
+		"Where to derive the SemVer increment from: labels, commits, or both (labels override commits)")
+	cmd.Flags().String("prerelease-identifier", "rc",
+		"Identifier used when cutting or continuing a prerelease (e.g. \"rc\" for v1.2.3-rc.1)")
+	cmd.Flags().Bool("release-notes", false,
+		"Publish a release on the SCM provider with generated release notes alongside the new tag (not used by the local backend)")
+	cmd.Flags().String("release-notes-template", "",
+		"Path to a JSON release notes template overriding the default section headings and ordering")
+	cmd.Flags().String("backend", "github",
+		"Backend to drive the workflow with: github (SCM API) or local (go-git against a checkout)")
+	cmd.Flags().String("provider", "",
+		"SCM provider to talk to when using the github backend: github, gitea, or gitlab. "+
+			"Defaults to auto-detecting from GITHUB_ACTIONS, GITLAB_CI, or GITEA_TOKEN")
+	cmd.Flags().Bool("allow-incompatible", false,
+		"Allow tagging a major version that doesn't match the module path's /vN suffix, "+
+			"marking the tag +incompatible instead of refusing it")
+	cmd.Flags().String("log-level", "info",
+		"Log verbosity: debug, info, warn, or error")
+	cmd.Flags().String("log-format", "text",
+		"Log output format: text or json")
+
+	return cmd
+}
+
+// detectProvider returns the SCM provider to use when none was given
+// explicitly, inferred from the CI environment variables each platform sets.
+func detectProvider() string {
+	if _, ok := os.LookupEnv("GITHUB_ACTIONS"); ok {
+		return "github"
+	}
+
+	if _, ok := os.LookupEnv("GITLAB_CI"); ok {
+		return "gitlab"
+	}
+
+	if _, ok := os.LookupEnv("GITEA_TOKEN"); ok {
+		return "gitea"
+	}
+
+	return "github"
+}
+
+// configureLogging sets the global log level and formatter from the
+// --log-level and --log-format flags.
+func configureLogging(level string, format string) {
+	parsedLevel, err := log.ParseLevel(level)
+	if err != nil {
+		log.Fatalf("ERROR: invalid log level: %s", level)
+	}
+	log.SetLevel(parsedLevel)
+
+	switch format {
+	case "text":
+		log.SetFormatter(&log.TextFormatter{})
+	case "json":
+		log.SetFormatter(&log.JSONFormatter{})
+	default:
+		log.Fatalf("ERROR: unknown log format: %s", format)
+	}
 }
 
 func executeCommand(cmd *cobra.Command, args []string) {
@@ -35,25 +147,145 @@ func executeCommand(cmd *cobra.Command, args []string) {
 	commitSha := args[2]
 	githubEventFilePath := args[3]
 
-	token, isExists := os.LookupEnv("GITHUB_TOKEN")
-	if !isExists {
-		log.Fatal("GITHUB_TOKEN env var does not exist")
+	logLevel, err := cmd.Flags().GetString("log-level")
+	if err != nil {
+		log.Fatalf("ERROR: %v", err)
+	}
+
+	logFormat, err := cmd.Flags().GetString("log-format")
+	if err != nil {
+		log.Fatalf("ERROR: %v", err)
+	}
+
+	configureLogging(logLevel, logFormat)
+
+	sourceFlag, err := cmd.Flags().GetString("source")
+	if err != nil {
+		log.Fatalf("ERROR: %v", err)
+	}
+
+	source, err := git.StringToIncrementSource(sourceFlag)
+	if err != nil {
+		log.Fatalf("ERROR: %v", err)
 	}
 
-	log.Printf("Workflow action arguments:")
-	log.Printf("  Repository:          %s", repository)
-	log.Printf("  ReleaseBranch:       %s", releaseBranch)
-	log.Printf("  CommitSha:           %s", commitSha)
-	log.Printf("  GithubEventFilePath: %s", githubEventFilePath)
-	log.Printf("  GITHUB_TOKEN:        ***[length = %d]***", len(token))
+	prereleaseIdentifier, err := cmd.Flags().GetString("prerelease-identifier")
+	if err != nil {
+		log.Fatalf("ERROR: %v", err)
+	}
 
-	client, err := git.New(token, repository, releaseBranch)
+	releaseNotes, err := cmd.Flags().GetBool("release-notes")
 	if err != nil {
 		log.Fatalf("ERROR: %v", err)
 	}
 
-	err = client.PerformAction(commitSha, githubEventFilePath)
+	releaseNotesTemplate, err := cmd.Flags().GetString("release-notes-template")
 	if err != nil {
 		log.Fatalf("ERROR: %v", err)
 	}
+
+	backend, err := cmd.Flags().GetString("backend")
+	if err != nil {
+		log.Fatalf("ERROR: %v", err)
+	}
+
+	providerFlag, err := cmd.Flags().GetString("provider")
+	if err != nil {
+		log.Fatalf("ERROR: %v", err)
+	}
+
+	provider := providerFlag
+	if provider == "" {
+		provider = detectProvider()
+	}
+
+	allowIncompatible, err := cmd.Flags().GetBool("allow-incompatible")
+	if err != nil {
+		log.Fatalf("ERROR: %v", err)
+	}
+
+	log.WithFields(log.Fields{
+		"repo":     repository,
+		"branch":   releaseBranch,
+		"commit":   commitSha,
+		"source":   source,
+		"backend":  backend,
+		"provider": provider,
+	}).Info("starting auto-semver-tag")
+
+	var client git.Client
+
+	switch backend {
+	case "github":
+		switch provider {
+		case "github":
+			token, isExists := os.LookupEnv("GITHUB_TOKEN")
+			if !isExists {
+				log.Fatal("GITHUB_TOKEN env var does not exist")
+			}
+			secrets.add(token)
+
+			client, err = git.New(token, repository, releaseBranch, source, prereleaseIdentifier, releaseNotes, releaseNotesTemplate, allowIncompatible)
+		case "gitea":
+			token, isExists := os.LookupEnv("GITEA_TOKEN")
+			if !isExists {
+				log.Fatal("GITEA_TOKEN env var does not exist")
+			}
+			secrets.add(token)
+
+			serverURL, isExists := os.LookupEnv("GITEA_SERVER_URL")
+			if !isExists {
+				log.Fatal("GITEA_SERVER_URL env var does not exist")
+			}
+
+			parts := strings.Split(repository, "/")
+
+			var scm *gitea.Client
+			scm, err = gitea.NewClient(serverURL, token, parts[0], parts[1])
+			if err == nil {
+				client, err = git.NewWithSCM(scm, releaseBranch, source, prereleaseIdentifier, releaseNotes, releaseNotesTemplate, allowIncompatible)
+			}
+		case "gitlab":
+			token, isExists := os.LookupEnv("GITLAB_TOKEN")
+			if !isExists {
+				log.Fatal("GITLAB_TOKEN env var does not exist")
+			}
+			secrets.add(token)
+
+			serverURL := os.Getenv("CI_SERVER_URL")
+			if serverURL == "" {
+				serverURL = "https://gitlab.com"
+			}
+
+			var scm *gitlab.Client
+			scm, err = gitlab.NewClient(serverURL, token, repository)
+			if err == nil {
+				client, err = git.NewWithSCM(scm, releaseBranch, source, prereleaseIdentifier, releaseNotes, releaseNotesTemplate, allowIncompatible)
+			}
+		default:
+			log.Fatalf("ERROR: unknown provider: %s", provider)
+		}
+	case "local":
+		client, err = git.NewLocal(repository, releaseBranch, prereleaseIdentifier, allowIncompatible)
+	default:
+		log.Fatalf("ERROR: unknown backend: %s", backend)
+	}
+
+	if err != nil {
+		log.Fatalf("ERROR: %v", err)
+	}
+
+	summary, err := client.PerformAction(commitSha, githubEventFilePath)
+	if err != nil {
+		log.Fatalf("ERROR: %v", err)
+	}
+
+	if summary != nil {
+		data, err := json.Marshal(summary)
+		if err != nil {
+			log.Fatalf("ERROR: %v", err)
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+	}
 }
@@ -0,0 +1,16 @@
+package git
+
+import "github.com/infobloxopen/auto-semver-tag/pkg/semver"
+
+// Client drives the end-to-end auto-semver-tag workflow against a single
+// backing repository: discover the latest SemVer tag, create a new one, and
+// react to a merged pull request event. RemoteClient and LocalClient are its
+// two implementations, selected by the --backend flag.
+type Client interface {
+	getLatestTag() (semver.SemVer, string, error)
+	createTag(version string, commitSha string) (semver.SemVer, error)
+	PerformAction(commitSha string, eventDataFilePath string) (*Summary, error)
+}
+
+var _ Client = (*RemoteClient)(nil)
+var _ Client = (*LocalClient)(nil)
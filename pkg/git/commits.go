@@ -0,0 +1,115 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/infobloxopen/auto-semver-tag/pkg/release"
+	"github.com/infobloxopen/auto-semver-tag/pkg/semver"
+)
+
+// IncrementSource selects where PerformAction should derive the SemVer
+// increment type from: the PR's labels, the commits it contains, or both
+// (labels taking precedence over commits when both are present).
+type IncrementSource string
+
+const (
+	IncrementSourceLabels  IncrementSource = "labels"
+	IncrementSourceCommits IncrementSource = "commits"
+	IncrementSourceBoth    IncrementSource = "both"
+)
+
+func StringToIncrementSource(val string) (IncrementSource, error) {
+	switch IncrementSource(val) {
+	case IncrementSourceLabels:
+		return IncrementSourceLabels, nil
+	case IncrementSourceCommits:
+		return IncrementSourceCommits, nil
+	case IncrementSourceBoth:
+		return IncrementSourceBoth, nil
+	default:
+		return "", fmt.Errorf("invalid increment source: %s", val)
+	}
+}
+
+// conventionalCommitRegExp captures the Conventional Commits header:
+// https://www.conventionalcommits.org/en/v1.0.0/#specification
+var conventionalCommitRegExp = regexp.MustCompile(
+	`^(feat|fix|chore|docs|refactor|perf|test|build|ci|style)(\([^)]*\))?(!)?:`,
+)
+
+var breakingChangeFooterRegExp = regexp.MustCompile(`(?m)^BREAKING CHANGE:`)
+
+// IncrementTypeForCommitMessages returns the SemVer increment implied by a
+// set of raw commit messages, parsed as Conventional Commits. It is used by
+// backends, such as the local go-git client, that have commit messages but
+// no pull request object to inspect.
+func IncrementTypeForCommitMessages(messages []string) semver.IncrementType {
+	category := release.CategoryOther
+	for _, message := range messages {
+		category = release.Highest(category, classifyCommitMessage(message))
+	}
+
+	return categoryToIncrementType(category)
+}
+
+func categoryToIncrementType(category release.Category) semver.IncrementType {
+	switch category {
+	case release.CategoryBreaking:
+		return semver.IncrementTypeMajor
+	case release.CategoryFeature:
+		return semver.IncrementTypeMinor
+	case release.CategoryFix:
+		return semver.IncrementTypePatch
+	default:
+		return semver.IncrementTypeUnknown
+	}
+}
+
+// classifyCommitMessage parses a single commit message as a Conventional
+// Commit and returns the release notes section it belongs in.
+func classifyCommitMessage(message string) release.Category {
+	lines := strings.SplitN(message, "\n", 2)
+	header := lines[0]
+
+	matches := conventionalCommitRegExp.FindStringSubmatch(header)
+	if matches == nil {
+		return release.CategoryOther
+	}
+
+	if matches[3] == "!" || breakingChangeFooterRegExp.MatchString(message) {
+		return release.CategoryBreaking
+	}
+
+	switch matches[1] {
+	case "feat":
+		return release.CategoryFeature
+	case "fix", "perf":
+		return release.CategoryFix
+	case "docs":
+		return release.CategoryDocs
+	default:
+		return release.CategoryOther
+	}
+}
+
+// ClassifyPullRequestCommits inspects every commit in the pull request and
+// returns the most significant release notes Category implied by their
+// Conventional Commits messages, so release notes are grouped by the same
+// signal that drove the version bump.
+func ClassifyPullRequestCommits(ctx context.Context, scm SCM, prNumber int) (release.Category, error) {
+	category := release.CategoryOther
+
+	messages, err := scm.ListPullRequestCommitMessages(ctx, prNumber)
+	if err != nil {
+		return release.CategoryOther, fmt.Errorf("failed to list commits for PR #%d: %v", prNumber, err)
+	}
+
+	for _, message := range messages {
+		category = release.Highest(category, classifyCommitMessage(message))
+	}
+
+	return category, nil
+}
@@ -6,153 +6,183 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"log"
+	"net/http"
 	"os"
 	"strings"
 
 	"github.com/google/go-github/v37/github"
-	"github.com/infobloxopen/auto-semver-tag/pkg/semver"
+	log "github.com/sirupsen/logrus"
 	"golang.org/x/oauth2"
 )
 
-type Repository struct {
-	name          string
-	owner         string
-	releaseBranch string
-	version       semver.SemVer
-	versionHash   string
-}
-
+// GithubClient implements SCM against the GitHub REST API.
 type GithubClient struct {
-	token  string
-	repo   Repository
+	owner  string
+	repo   string
 	client *github.Client
 }
 
-func New(token string, repository string, releaseBranch string) (*GithubClient, error) {
+var _ SCM = (*GithubClient)(nil)
+
+// NewGithubClient builds a GithubClient authenticated with token, targeting
+// owner/repo.
+func NewGithubClient(token string, owner string, repo string) *GithubClient {
 	ctx := context.Background()
 
 	tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token, TokenType: "token"})
 	client := github.NewClient(oauth2.NewClient(ctx, tokenSource))
 
-	parts := strings.Split(repository, "/")
-	owner := parts[0]
-	repoName := parts[1]
+	return &GithubClient{
+		owner:  owner,
+		repo:   repo,
+		client: client,
+	}
+}
 
-	version, commit, err := getLatestTag(client, owner, repoName)
+func (g *GithubClient) ListTags(ctx context.Context) (map[string]string, error) {
+	refs, response, err := g.client.Git.ListMatchingRefs(ctx, g.owner, g.repo, &github.ReferenceListOptions{
+		Ref: "tags",
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	repo := Repository{
-		repoName,
-		owner,
-		releaseBranch,
-		version,
-		commit,
+	scopes := response.Header.Get("X-OAuth-Scopes")
+	log.WithField("scopes", scopes).Debug("github client authorized")
+
+	tags := map[string]string{}
+	for _, ref := range refs {
+		if ref.Object == nil || ref.Object.SHA == nil {
+			continue
+		}
+
+		tags[strings.TrimPrefix(*ref.Ref, "refs/tags/")] = *ref.Object.SHA
 	}
 
-	return &GithubClient{
-		token,
-		repo,
-		client,
-	}, nil
+	return tags, nil
 }
 
-func (g *GithubClient) PerformAction(commitSha string, eventDataFilePath string) error {
-	log.Printf("Extracting event data")
+func (g *GithubClient) ListPullRequestCommitMessages(ctx context.Context, prNumber int) ([]string, error) {
+	var messages []string
 
-	event, err := parseEventDataFile(eventDataFilePath)
-	if err != nil {
-		return err
-	}
-
-	pr := event.PullRequest
-	if pr == nil {
-		return fmt.Errorf("pull request not found in data file: %v", event)
-	}
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		commits, response, err := g.client.PullRequests.ListCommits(ctx, g.owner, g.repo, prNumber, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list commits for PR #%d: %v", prNumber, err)
+		}
 
-	action := ""
-	if event.Action != nil {
-		action = *event.Action
-	}
+		for _, commit := range commits {
+			if commit.Commit != nil && commit.Commit.Message != nil {
+				messages = append(messages, *commit.Commit.Message)
+			}
+		}
 
-	isMerged := false
-	if pr.Merged != nil {
-		isMerged = *pr.Merged
+		if response.NextPage == 0 {
+			break
+		}
+		opts.Page = response.NextPage
 	}
 
-	baseRef := ""
-	if pr.Base != nil && pr.Base.Ref != nil {
-		baseRef = *pr.Base.Ref
-	}
+	return messages, nil
+}
 
-	mergeCommit := pr.GetMergeCommitSHA()
+func (g *GithubClient) ListMergedPullRequests(ctx context.Context, baseBranch string, previousCommit string, newCommit string) ([]*PullRequest, error) {
+	commitSHAs := map[string]bool{newCommit: true}
 
-	log.Printf("Event pull request:")
-	log.Printf("  Action:          %s", action)
-	log.Printf("  IsMerged:        %v", isMerged)
-	log.Printf("  Base Ref:        %s", baseRef)
-	log.Printf("  Merge Commit:    %s", mergeCommit)
-	log.Printf("  Workflow Commit: %s", commitSha)
+	if previousCommit != "" {
+		comparison, _, err := g.client.Repositories.CompareCommits(ctx, g.owner, g.repo, previousCommit, newCommit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare %s...%s: %v", previousCommit, newCommit, err)
+		}
 
-	if action != "closed" {
-		return fmt.Errorf("pull request is not closed: %s", action)
+		for _, commit := range comparison.Commits {
+			if commit.SHA != nil {
+				commitSHAs[*commit.SHA] = true
+			}
+		}
 	}
 
-	if !isMerged {
-		return fmt.Errorf("pull request is not merged")
+	var merged []*PullRequest
+	opts := &github.PullRequestListOptions{
+		State:       "closed",
+		Base:        baseBranch,
+		Sort:        "updated",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
 	}
 
-	if baseRef != g.repo.releaseBranch {
-		return fmt.Errorf("pull request merged into a different branch (expected: %s, actual: %s)",
-			g.repo.releaseBranch, baseRef)
-	}
+	for {
+		prs, response, err := g.client.PullRequests.List(ctx, g.owner, g.repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull requests: %v", err)
+		}
 
-	if mergeCommit != commitSha {
-		return fmt.Errorf("workflow run arguments and pull request data mismatch")
-	}
+		for _, pr := range prs {
+			if pr.Merged == nil || !*pr.Merged {
+				continue
+			}
 
-	if mergeCommit == g.repo.versionHash {
-		log.Printf("Detected this commit has already been tagged with the latest version. No new tag necessary.")
+			if commitSHAs[pr.GetMergeCommitSHA()] {
+				merged = append(merged, toPullRequest(pr))
+			}
+		}
 
-		return nil
+		if response.NextPage == 0 {
+			break
+		}
+		opts.Page = response.NextPage
 	}
 
-	log.Printf("Extracting SemVer labels from pull request...")
+	return merged, nil
+}
+
+func (g *GithubClient) ParseEvent(eventFilePath string) (*PullRequestEvent, error) {
+	file, err := os.Open(eventFilePath)
+	defer func() { _ = file.Close() }()
 
-	incrementType := parsePullRequestLabels(pr)
-	if incrementType == semver.IncrementTypeUnknown {
-		log.Printf(`No SemVer labels found. Commit will still be using %s`, g.repo.version)
+	if err != nil {
+		return nil, fmt.Errorf("%s. Filepath: %s", err, eventFilePath)
+	}
 
-		return nil
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s. Filepath: %s", err, eventFilePath)
 	}
 
-	log.Printf(`Found "%s" label.`, incrementType)
+	eventData, err := github.ParseWebHook("pull_request", stripOrg(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s. Filepath: %s", err, eventFilePath)
+	}
 
-	newVersion := g.repo.version.IncrementVersion(incrementType)
+	event, ok := eventData.(*github.PullRequestEvent)
+	if !ok {
+		return nil, errors.New("could not parse GitHub event into a PullRequestEvent")
+	}
 
-	log.Printf("Incrementing to new version: %s", newVersion)
+	action := ""
+	if event.Action != nil {
+		action = *event.Action
+	}
 
-	err = g.createTag(newVersion.String(), commitSha)
-	if err != nil {
-		return err
+	var pr *PullRequest
+	if event.PullRequest != nil {
+		pr = toPullRequest(event.PullRequest)
 	}
 
-	return nil
+	return &PullRequestEvent{Action: action, PullRequest: pr}, nil
 }
 
-func (g *GithubClient) createTag(version string, commitSha string) error {
-	ctx := context.Background()
-	refValue := fmt.Sprintf("refs/tags/%s", version)
+func (g *GithubClient) CreateTag(ctx context.Context, tagName string, commitSHA string) error {
+	refValue := fmt.Sprintf("refs/tags/%s", tagName)
 	ref := &github.Reference{
 		Ref: github.String(refValue),
 		Object: &github.GitObject{
-			SHA: &commitSha,
+			SHA: &commitSHA,
 		},
 	}
 
-	_, _, err := g.client.Git.CreateRef(ctx, g.repo.owner, g.repo.name, ref)
+	_, _, err := g.client.Git.CreateRef(ctx, g.owner, g.repo, ref)
 	if err != nil {
 		return fmt.Errorf("failed to create new ref (%s): %v", refValue, err)
 	}
@@ -160,90 +190,79 @@ func (g *GithubClient) createTag(version string, commitSha string) error {
 	return nil
 }
 
-func parsePullRequestLabels(pr *github.PullRequest) semver.IncrementType {
-	incType := semver.IncrementTypeUnknown
-	for _, label := range pr.Labels {
-		if label.Name == nil {
-			continue
-		}
-
-		t := semver.StringToIncrementType(*label.Name)
+func (g *GithubClient) CreateRelease(ctx context.Context, tagName string, body string) error {
+	rel := &github.RepositoryRelease{
+		TagName: github.String(tagName),
+		Name:    github.String(tagName),
+		Body:    github.String(body),
+	}
 
-		if t < incType {
-			incType = t
-		}
+	_, _, err := g.client.Repositories.CreateRelease(ctx, g.owner, g.repo, rel)
+	if err != nil {
+		return fmt.Errorf("failed to create release %s: %v", tagName, err)
 	}
 
-	return incType
+	return nil
 }
 
-func parseEventDataFile(filePath string) (*github.PullRequestEvent, error) {
-	file, err := os.Open(filePath)
-	defer func() { _ = file.Close() }()
+func (g *GithubClient) CompareURL(previousTag string, newTag string) string {
+	return fmt.Sprintf("https://github.com/%s/%s/compare/%s...%s", g.owner, g.repo, previousTag, newTag)
+}
 
+func (g *GithubClient) ReadFile(ctx context.Context, ref string, path string) (string, bool, error) {
+	file, _, response, err := g.client.Repositories.GetContents(ctx, g.owner, g.repo, path, &github.RepositoryContentGetOptions{Ref: ref})
 	if err != nil {
-		return nil, fmt.Errorf("%s. Filepath: %s", err, filePath)
-	}
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return "", false, nil
+		}
 
-	event, err := ioutil.ReadAll(file)
-	if err != nil {
-		return nil, fmt.Errorf("%s. Filepath: %s", err, filePath)
+		return "", false, fmt.Errorf("failed to read %s at %s: %v", path, ref, err)
 	}
 
-	eventData, err := github.ParseWebHook("pull_request", stripOrg(event))
-	if err != nil {
-		return nil, fmt.Errorf("%s. Filepath: %s", err, filePath)
+	if file == nil {
+		return "", false, nil
 	}
 
-	res, ok := eventData.(*github.PullRequestEvent)
-	if !ok {
-		return nil, errors.New("could not parse GitHub event into a PullRequestEvent")
+	content, err := file.GetContent()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to decode %s at %s: %v", path, ref, err)
 	}
 
-	return res, nil
+	return content, true, nil
 }
 
-func getLatestTag(client *github.Client, owner string, repo string) (semver.SemVer, string, error) {
-	res := semver.SemVer{}
-	commit := ""
-	ctx := context.Background()
-
-	refs, response, err := client.Git.ListMatchingRefs(ctx, owner, repo, &github.ReferenceListOptions{
-		Ref: "tags",
-	})
-
-	scopes := response.Header.Get("X-OAuth-Scopes")
-	log.Printf("GitHub client authorized for scopes: %s", scopes)
-
-	for k, v := range response.Header {
-		log.Printf("Header: %-32s %v", k, v)
+func toPullRequest(pr *github.PullRequest) *PullRequest {
+	labels := make([]string, 0, len(pr.Labels))
+	for _, label := range pr.Labels {
+		if label.Name != nil {
+			labels = append(labels, *label.Name)
+		}
 	}
 
-	if err != nil {
-		return res, commit, err
+	baseBranch := ""
+	if pr.Base != nil && pr.Base.Ref != nil {
+		baseBranch = *pr.Base.Ref
 	}
 
-	for _, ref := range refs {
-		version, err := semver.New(strings.Replace(*ref.Ref, "refs/tags/", "", 1))
-		if err != nil {
-			log.Printf("Ignoring tag: %s", *ref.Ref)
-
-			continue
-		}
-
-		if version.IsGreaterThan(res) {
-			if ref.Object == nil || ref.Object.SHA == nil {
-				return res, commit, fmt.Errorf("unable to extract hash from tag: %s", version)
-			}
-
-			res = version
-			commit = *ref.Object.SHA
-		}
+	author := ""
+	if pr.User != nil && pr.User.Login != nil {
+		author = *pr.User.Login
 	}
 
-	log.Printf("Found previous version tag: %s (commit: %s)", res, commit)
+	merged := false
+	if pr.Merged != nil {
+		merged = *pr.Merged
+	}
 
-	return res, commit, nil
+	return &PullRequest{
+		Number:      pr.GetNumber(),
+		Title:       pr.GetTitle(),
+		Author:      author,
+		Labels:      labels,
+		BaseBranch:  baseBranch,
+		MergeCommit: pr.GetMergeCommitSHA(),
+		Merged:      merged,
+	}
 }
 
 func stripOrg(byteString []byte) []byte {
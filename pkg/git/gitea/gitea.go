@@ -0,0 +1,281 @@
+// Package gitea implements the git.SCM interface against a Gitea instance,
+// so auto-semver-tag can drive the same workflow from Gitea Actions as it
+// does from GitHub Actions.
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"code.gitea.io/sdk/gitea"
+
+	"github.com/infobloxopen/auto-semver-tag/pkg/git"
+)
+
+// Client implements git.SCM against the Gitea API.
+type Client struct {
+	owner  string
+	repo   string
+	server string
+	client *gitea.Client
+}
+
+var _ git.SCM = (*Client)(nil)
+
+// NewClient builds a Client authenticated with token against the Gitea
+// instance at serverURL, targeting owner/repo.
+func NewClient(serverURL string, token string, owner string, repo string) (*Client, error) {
+	client, err := gitea.NewClient(serverURL, gitea.SetToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gitea client: %v", err)
+	}
+
+	return &Client{
+		owner:  owner,
+		repo:   repo,
+		server: strings.TrimSuffix(serverURL, "/"),
+		client: client,
+	}, nil
+}
+
+func (c *Client) ListTags(ctx context.Context) (map[string]string, error) {
+	tags := map[string]string{}
+
+	page := 1
+	for {
+		repoTags, _, err := c.client.ListRepoTags(c.owner, c.repo, gitea.ListRepoTagsOptions{
+			ListOptions: gitea.ListOptions{Page: page, PageSize: 50},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags: %v", err)
+		}
+
+		for _, tag := range repoTags {
+			if tag.Commit == nil {
+				continue
+			}
+
+			tags[tag.Name] = tag.Commit.SHA
+		}
+
+		if len(repoTags) < 50 {
+			break
+		}
+		page++
+	}
+
+	return tags, nil
+}
+
+func (c *Client) ListPullRequestCommitMessages(ctx context.Context, prNumber int) ([]string, error) {
+	var messages []string
+
+	page := 1
+	for {
+		commits, _, err := c.client.ListPullRequestCommits(c.owner, c.repo, int64(prNumber), gitea.ListPullRequestCommitsOptions{
+			ListOptions: gitea.ListOptions{Page: page, PageSize: 50},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list commits for PR #%d: %v", prNumber, err)
+		}
+
+		for _, commit := range commits {
+			if commit.RepoCommit != nil {
+				messages = append(messages, commit.RepoCommit.Message)
+			}
+		}
+
+		if len(commits) < 50 {
+			break
+		}
+		page++
+	}
+
+	return messages, nil
+}
+
+func (c *Client) ListMergedPullRequests(ctx context.Context, baseBranch string, previousCommit string, newCommit string) ([]*git.PullRequest, error) {
+	commitSHAs, err := c.commitSHAsBetween(previousCommit, newCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	var merged []*git.PullRequest
+
+	page := 1
+	for {
+		prs, _, err := c.client.ListRepoPullRequests(c.owner, c.repo, gitea.ListPullRequestsOptions{
+			ListOptions: gitea.ListOptions{Page: page, PageSize: 50},
+			State:       gitea.StateClosed,
+			Sort:        "recentupdate",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull requests: %v", err)
+		}
+
+		for _, pr := range prs {
+			if !pr.HasMerged || pr.MergedCommitID == nil {
+				continue
+			}
+
+			if pr.Base == nil || pr.Base.Name != baseBranch {
+				continue
+			}
+
+			if !commitSHAs[*pr.MergedCommitID] {
+				continue
+			}
+
+			merged = append(merged, toPullRequest(pr))
+		}
+
+		if len(prs) < 50 {
+			break
+		}
+		page++
+	}
+
+	return merged, nil
+}
+
+// commitSHAsBetween returns the set of commit SHAs reachable from newCommit
+// down to, but excluding, previousCommit, mirroring the range the GitHub
+// backend builds with CompareCommits so a PR's merge commit is only matched
+// when it actually landed in this release.
+func (c *Client) commitSHAsBetween(previousCommit string, newCommit string) (map[string]bool, error) {
+	shas := map[string]bool{newCommit: true}
+
+	if previousCommit == "" {
+		return shas, nil
+	}
+
+	page := 1
+	for {
+		commits, _, err := c.client.ListRepoCommits(c.owner, c.repo, gitea.ListCommitOptions{
+			ListOptions: gitea.ListOptions{Page: page, PageSize: 50},
+			SHA:         newCommit,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list commits %s..%s: %v", previousCommit, newCommit, err)
+		}
+
+		for _, commit := range commits {
+			if commit.SHA == previousCommit {
+				return shas, nil
+			}
+
+			shas[commit.SHA] = true
+		}
+
+		if len(commits) < 50 {
+			return shas, nil
+		}
+		page++
+	}
+}
+
+func (c *Client) ParseEvent(eventFilePath string) (*git.PullRequestEvent, error) {
+	file, err := os.Open(eventFilePath)
+	defer func() { _ = file.Close() }()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s. Filepath: %s", err, eventFilePath)
+	}
+
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s. Filepath: %s", err, eventFilePath)
+	}
+
+	var payload gitea.PullRequestPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse gitea event: %s. Filepath: %s", err, eventFilePath)
+	}
+
+	return &git.PullRequestEvent{
+		Action:      string(payload.Action),
+		PullRequest: toPullRequest(payload.PullRequest),
+	}, nil
+}
+
+func (c *Client) CreateTag(ctx context.Context, tagName string, commitSHA string) error {
+	_, _, err := c.client.CreateTag(c.owner, c.repo, gitea.CreateTagOption{
+		TagName: tagName,
+		Target:  commitSHA,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create tag %s: %v", tagName, err)
+	}
+
+	return nil
+}
+
+func (c *Client) CreateRelease(ctx context.Context, tagName string, body string) error {
+	_, _, err := c.client.NewRelease(c.owner, c.repo, gitea.CreateReleaseOption{
+		TagName: tagName,
+		Title:   tagName,
+		Note:    body,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create release %s: %v", tagName, err)
+	}
+
+	return nil
+}
+
+func (c *Client) CompareURL(previousTag string, newTag string) string {
+	return fmt.Sprintf("%s/%s/%s/compare/%s...%s", c.server, c.owner, c.repo, previousTag, newTag)
+}
+
+func (c *Client) ReadFile(ctx context.Context, ref string, path string) (string, bool, error) {
+	data, response, err := c.client.GetFile(c.owner, c.repo, ref, path)
+	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			return "", false, nil
+		}
+
+		return "", false, fmt.Errorf("failed to read %s at %s: %v", path, ref, err)
+	}
+
+	return string(data), true, nil
+}
+
+func toPullRequest(pr *gitea.PullRequest) *git.PullRequest {
+	if pr == nil {
+		return nil
+	}
+
+	labels := make([]string, 0, len(pr.Labels))
+	for _, label := range pr.Labels {
+		labels = append(labels, label.Name)
+	}
+
+	baseBranch := ""
+	if pr.Base != nil {
+		baseBranch = pr.Base.Name
+	}
+
+	author := ""
+	if pr.Poster != nil {
+		author = pr.Poster.UserName
+	}
+
+	mergeCommit := ""
+	if pr.MergedCommitID != nil {
+		mergeCommit = *pr.MergedCommitID
+	}
+
+	return &git.PullRequest{
+		Number:      int(pr.Index),
+		Title:       pr.Title,
+		Author:      author,
+		Labels:      labels,
+		BaseBranch:  baseBranch,
+		MergeCommit: mergeCommit,
+		Merged:      pr.HasMerged,
+	}
+}
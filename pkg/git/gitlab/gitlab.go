@@ -0,0 +1,274 @@
+// Package gitlab implements the git.SCM interface against a GitLab instance,
+// so auto-semver-tag can drive the same workflow from GitLab CI as it does
+// from GitHub Actions.
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/infobloxopen/auto-semver-tag/pkg/git"
+)
+
+// Client implements git.SCM against the GitLab API.
+type Client struct {
+	projectPath string
+	server      string
+	client      *gitlab.Client
+}
+
+// NewClient builds a Client authenticated with token against the GitLab
+// instance at serverURL, targeting projectPath (e.g. "group/project").
+func NewClient(serverURL string, token string, projectPath string) (*Client, error) {
+	client, err := gitlab.NewClient(token, gitlab.WithBaseURL(serverURL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gitlab client: %v", err)
+	}
+
+	return &Client{
+		projectPath: projectPath,
+		server:      strings.TrimSuffix(serverURL, "/"),
+		client:      client,
+	}, nil
+}
+
+var _ git.SCM = (*Client)(nil)
+
+func (c *Client) ListTags(ctx context.Context) (map[string]string, error) {
+	tags := map[string]string{}
+
+	opts := &gitlab.ListTagsOptions{ListOptions: gitlab.ListOptions{PerPage: 100}}
+	for {
+		projectTags, response, err := c.client.Tags.ListTags(c.projectPath, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags: %v", err)
+		}
+
+		for _, tag := range projectTags {
+			if tag.Commit == nil {
+				continue
+			}
+
+			tags[tag.Name] = tag.Commit.ID
+		}
+
+		if response.NextPage == 0 {
+			break
+		}
+		opts.Page = response.NextPage
+	}
+
+	return tags, nil
+}
+
+func (c *Client) ListPullRequestCommitMessages(ctx context.Context, prNumber int) ([]string, error) {
+	var messages []string
+
+	opts := &gitlab.GetMergeRequestCommitsOptions{PerPage: 100}
+	for {
+		commits, response, err := c.client.MergeRequests.GetMergeRequestCommits(c.projectPath, prNumber, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list commits for merge request !%d: %v", prNumber, err)
+		}
+
+		for _, commit := range commits {
+			messages = append(messages, commit.Message)
+		}
+
+		if response.NextPage == 0 {
+			break
+		}
+		opts.Page = response.NextPage
+	}
+
+	return messages, nil
+}
+
+func (c *Client) ListMergedPullRequests(ctx context.Context, baseBranch string, previousCommit string, newCommit string) ([]*git.PullRequest, error) {
+	commitSHAs, err := c.commitSHAsBetween(previousCommit, newCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	var merged []*git.PullRequest
+
+	state := "merged"
+	opts := &gitlab.ListProjectMergeRequestsOptions{
+		ListOptions:  gitlab.ListOptions{PerPage: 100},
+		TargetBranch: &baseBranch,
+		State:        &state,
+		OrderBy:      gitlab.String("updated_at"),
+	}
+
+	for {
+		mrs, response, err := c.client.MergeRequests.ListProjectMergeRequests(c.projectPath, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list merge requests: %v", err)
+		}
+
+		for _, mr := range mrs {
+			if !commitSHAs[mr.MergeCommitSHA] {
+				continue
+			}
+
+			merged = append(merged, toPullRequest(mr))
+		}
+
+		if response.NextPage == 0 {
+			break
+		}
+		opts.Page = response.NextPage
+	}
+
+	return merged, nil
+}
+
+// commitSHAsBetween returns the set of commit SHAs reachable from newCommit
+// down to, but excluding, previousCommit, mirroring the range the GitHub
+// backend builds with CompareCommits so a merge request's merge commit is
+// only matched when it actually landed in this release.
+func (c *Client) commitSHAsBetween(previousCommit string, newCommit string) (map[string]bool, error) {
+	shas := map[string]bool{newCommit: true}
+
+	if previousCommit == "" {
+		return shas, nil
+	}
+
+	comparison, _, err := c.client.Repositories.Compare(c.projectPath, &gitlab.CompareOptions{
+		From: &previousCommit,
+		To:   &newCommit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %s...%s: %v", previousCommit, newCommit, err)
+	}
+
+	for _, commit := range comparison.Commits {
+		shas[commit.ID] = true
+	}
+
+	return shas, nil
+}
+
+// gitlabMergeRequestEvent mirrors the subset of GitLab's Merge Request Hook
+// payload (https://docs.gitlab.com/ee/user/project/integrations/webhook_events.html#merge-request-events)
+// that PerformAction needs.
+type gitlabMergeRequestEvent struct {
+	ObjectAttributes struct {
+		IID            int    `json:"iid"`
+		Title          string `json:"title"`
+		Action         string `json:"action"`
+		State          string `json:"state"`
+		TargetBranch   string `json:"target_branch"`
+		MergeCommitSHA string `json:"merge_commit_sha"`
+	} `json:"object_attributes"`
+	User struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Labels []struct {
+		Title string `json:"title"`
+	} `json:"labels"`
+}
+
+func (c *Client) ParseEvent(eventFilePath string) (*git.PullRequestEvent, error) {
+	file, err := os.Open(eventFilePath)
+	defer func() { _ = file.Close() }()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s. Filepath: %s", err, eventFilePath)
+	}
+
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s. Filepath: %s", err, eventFilePath)
+	}
+
+	var event gitlabMergeRequestEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("failed to parse gitlab event: %s. Filepath: %s", err, eventFilePath)
+	}
+
+	labels := make([]string, 0, len(event.Labels))
+	for _, label := range event.Labels {
+		labels = append(labels, label.Title)
+	}
+
+	action := event.ObjectAttributes.Action
+	if event.ObjectAttributes.State == "merged" {
+		// GitLab reports the merge itself as action "merge", but PerformAction
+		// expects the same "closed" action GitHub and Gitea use for a
+		// terminal pull/merge request.
+		action = "closed"
+	}
+
+	return &git.PullRequestEvent{
+		Action: action,
+		PullRequest: &git.PullRequest{
+			Number:      event.ObjectAttributes.IID,
+			Title:       event.ObjectAttributes.Title,
+			Author:      event.User.Username,
+			Labels:      labels,
+			BaseBranch:  event.ObjectAttributes.TargetBranch,
+			MergeCommit: event.ObjectAttributes.MergeCommitSHA,
+			Merged:      event.ObjectAttributes.State == "merged",
+		},
+	}, nil
+}
+
+func (c *Client) CreateTag(ctx context.Context, tagName string, commitSHA string) error {
+	_, _, err := c.client.Tags.CreateTag(c.projectPath, &gitlab.CreateTagOptions{
+		TagName: &tagName,
+		Ref:     &commitSHA,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create tag %s: %v", tagName, err)
+	}
+
+	return nil
+}
+
+func (c *Client) CreateRelease(ctx context.Context, tagName string, body string) error {
+	_, _, err := c.client.Releases.CreateRelease(c.projectPath, &gitlab.CreateReleaseOptions{
+		TagName:     &tagName,
+		Description: &body,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create release %s: %v", tagName, err)
+	}
+
+	return nil
+}
+
+func (c *Client) CompareURL(previousTag string, newTag string) string {
+	return fmt.Sprintf("%s/%s/-/compare/%s...%s", c.server, c.projectPath, previousTag, newTag)
+}
+
+func (c *Client) ReadFile(ctx context.Context, ref string, path string) (string, bool, error) {
+	data, response, err := c.client.RepositoryFiles.GetRawFile(c.projectPath, path, &gitlab.GetRawFileOptions{Ref: &ref})
+	if err != nil {
+		if response != nil && response.StatusCode == 404 {
+			return "", false, nil
+		}
+
+		return "", false, fmt.Errorf("failed to read %s at %s: %v", path, ref, err)
+	}
+
+	return string(data), true, nil
+}
+
+func toPullRequest(mr *gitlab.MergeRequest) *git.PullRequest {
+	return &git.PullRequest{
+		Number:      mr.IID,
+		Title:       mr.Title,
+		Author:      mr.Author.Username,
+		Labels:      mr.Labels,
+		BaseBranch:  mr.TargetBranch,
+		MergeCommit: mr.MergeCommitSHA,
+		Merged:      mr.State == "merged",
+	}
+}
@@ -0,0 +1,73 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/infobloxopen/auto-semver-tag/pkg/semver"
+)
+
+var moduleDirectiveRegExp = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+var moduleMajorSuffixRegExp = regexp.MustCompile(`^(.*)/v(\d+)$`)
+
+// expectedModuleMajor returns the major version a Go module's import path
+// commits it to: the numeric /vN suffix when present, or 1 when absent,
+// since Go's module rules don't require a suffix for v0 or v1.
+func expectedModuleMajor(modulePath string) uint64 {
+	matches := moduleMajorSuffixRegExp.FindStringSubmatch(modulePath)
+	if matches == nil {
+		return 1
+	}
+
+	major, err := strconv.ParseUint(matches[2], 10, 64)
+	if err != nil {
+		return 1
+	}
+
+	return major
+}
+
+// modulePath extracts the path from a go.mod file's "module" directive.
+func modulePath(goModContents string) string {
+	matches := moduleDirectiveRegExp.FindStringSubmatch(goModContents)
+	if matches == nil {
+		return ""
+	}
+
+	return matches[1]
+}
+
+// resolveTagVersion checks version against the module path declared by the
+// go.mod at the commit being tagged (goModContents/goModOK, as returned by
+// an SCM.ReadFile or equivalent lookup; goModOK is false when no go.mod
+// exists at that commit). A major version that doesn't match the module's
+// import path is the classic Go modules footgun: consumers importing the
+// unsuffixed path silently keep resolving the old major. It is refused
+// unless allowIncompatible is set, in which case the tag is marked
+// "+incompatible", the same way `go` treats such versions when resolving
+// them.
+func resolveTagVersion(version semver.SemVer, goModContents string, goModOK bool, allowIncompatible bool) (semver.SemVer, error) {
+	if version.Major() < 2 {
+		return version, nil
+	}
+
+	path := ""
+	if goModOK {
+		path = modulePath(goModContents)
+	}
+
+	expected := expectedModuleMajor(path)
+	if expected == version.Major() {
+		return version, nil
+	}
+
+	if !allowIncompatible {
+		return version, fmt.Errorf(
+			"refusing to tag %s: the module path (%q) implies major version v%d; "+
+				"update go.mod's module path to end in /v%d, or pass --allow-incompatible to tag it as +incompatible",
+			version, path, expected, version.Major())
+	}
+
+	return version.WithBuild("incompatible"), nil
+}
@@ -0,0 +1,322 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infobloxopen/auto-semver-tag/pkg/semver"
+)
+
+// mergeCommitPRRegExp recognizes GitHub's default merge commit subject line,
+// e.g. "Merge pull request #42 from owner/feature-branch".
+var mergeCommitPRRegExp = regexp.MustCompile(`Merge pull request #(\d+)`)
+
+// LocalClient drives auto-semver-tag from a local clone using go-git instead
+// of the GitHub API, so it can run in CI systems other than GitHub Actions
+// (GitLab CI, Jenkins, ...) where only a checkout is available.
+type LocalClient struct {
+	repoPath             string
+	releaseBranch        string
+	prereleaseIdentifier string
+	allowIncompatible    bool
+	repo                 *git.Repository
+	version              semver.SemVer
+	versionHash          string
+}
+
+// NewLocal opens the git repository at repoPath and resolves its latest
+// SemVer tag.
+func NewLocal(repoPath string, releaseBranch string, prereleaseIdentifier string, allowIncompatible bool) (*LocalClient, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open local repository at %s: %v", repoPath, err)
+	}
+
+	lc := &LocalClient{
+		repoPath:             repoPath,
+		releaseBranch:        releaseBranch,
+		prereleaseIdentifier: prereleaseIdentifier,
+		allowIncompatible:    allowIncompatible,
+		repo:                 repo,
+	}
+
+	lc.version, lc.versionHash, err = lc.getLatestTag()
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{"tag": lc.version.String(), "commit": lc.versionHash}).Info("found previous version tag")
+
+	return lc, nil
+}
+
+func (lc *LocalClient) getLatestTag() (semver.SemVer, string, error) {
+	res := semver.SemVer{}
+	commit := ""
+
+	tagRefs, err := lc.repo.Tags()
+	if err != nil {
+		return res, commit, fmt.Errorf("failed to list tags: %v", err)
+	}
+
+	err = tagRefs.ForEach(func(ref *plumbing.Reference) error {
+		name := strings.TrimPrefix(ref.Name().String(), "refs/tags/")
+
+		version, err := semver.New(name)
+		if err != nil {
+			return nil
+		}
+
+		if !version.IsGreaterThan(res) {
+			return nil
+		}
+
+		hash := ref.Hash()
+		if tagObj, err := lc.repo.TagObject(hash); err == nil {
+			hash = tagObj.Target
+		}
+
+		res = version
+		commit = hash.String()
+
+		return nil
+	})
+	if err != nil {
+		return res, commit, err
+	}
+
+	return res, commit, nil
+}
+
+// readFile returns the contents of path as of the commit at ref, or
+// ok=false when path does not exist there.
+func (lc *LocalClient) readFile(ref string, path string) (contents string, ok bool, err error) {
+	commit, err := lc.repo.CommitObject(plumbing.NewHash(ref))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to resolve commit %s: %v", ref, err)
+	}
+
+	file, err := commit.File(path)
+	if err != nil {
+		if err == object.ErrFileNotFound {
+			return "", false, nil
+		}
+
+		return "", false, fmt.Errorf("failed to read %s at %s: %v", path, ref, err)
+	}
+
+	contents, err = file.Contents()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read %s at %s: %v", path, ref, err)
+	}
+
+	return contents, true, nil
+}
+
+// createTag creates the tag for version, resolved against the go.mod at
+// commitSha to guard against a major-version mismatch (see
+// resolveTagVersion). The returned SemVer is the one actually tagged, which
+// may differ from version when it was marked +incompatible.
+func (lc *LocalClient) createTag(version string, commitSha string) (semver.SemVer, error) {
+	parsed, err := semver.New(version)
+	if err != nil {
+		return semver.SemVer{}, err
+	}
+
+	goModContents, goModOK, err := lc.readFile(commitSha, "go.mod")
+	if err != nil {
+		return semver.SemVer{}, err
+	}
+
+	resolved, err := resolveTagVersion(parsed, goModContents, goModOK, lc.allowIncompatible)
+	if err != nil {
+		return semver.SemVer{}, err
+	}
+
+	version = resolved.String()
+
+	hash := plumbing.NewHash(commitSha)
+
+	_, err = lc.repo.CreateTag(version, hash, &git.CreateTagOptions{
+		Tagger:  &object.Signature{Name: "auto-semver-tag", When: time.Now()},
+		Message: version,
+	})
+	if err != nil {
+		return semver.SemVer{}, fmt.Errorf("failed to create local tag %s: %v", version, err)
+	}
+
+	auth, err := localPushAuth()
+	if err != nil {
+		return semver.SemVer{}, err
+	}
+
+	refSpec := config.RefSpec(fmt.Sprintf("refs/tags/%s:refs/tags/%s", version, version))
+
+	err = lc.repo.Push(&git.PushOptions{
+		RemoteName: "origin",
+		RefSpecs:   []config.RefSpec{refSpec},
+		Auth:       auth,
+	})
+	if err != nil {
+		return semver.SemVer{}, fmt.Errorf("failed to push tag %s: %v", version, err)
+	}
+
+	return resolved, nil
+}
+
+func (lc *LocalClient) PerformAction(commitSha string, eventDataFilePath string) (*Summary, error) {
+	_ = eventDataFilePath // the local backend has no GitHub event payload to read
+
+	entry := log.WithField("commit", commitSha)
+
+	if commitSha == lc.versionHash {
+		entry.Info("commit is already tagged with the latest version, no new tag necessary")
+
+		return nil, nil
+	}
+
+	hash := plumbing.NewHash(commitSha)
+
+	commit, err := lc.repo.CommitObject(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve commit %s: %v", commitSha, err)
+	}
+
+	prNumber := lc.resolvePullRequestNumber(commit)
+	entry = entry.WithField("pr", prNumber)
+	entry.Debug("resolved merge commit")
+
+	messages, err := lc.commitMessagesSince(lc.versionHash, commitSha)
+	if err != nil {
+		return nil, err
+	}
+
+	incrementType := IncrementTypeForCommitMessages(messages)
+	if incrementType == semver.IncrementTypeUnknown {
+		entry.WithField("tag", lc.version.String()).Info("no Conventional Commits found, keeping current version")
+
+		return nil, nil
+	}
+
+	entry = entry.WithField("increment", incrementType)
+	entry.Info("determined increment type")
+
+	newVersion := lc.version.IncrementVersion(incrementType, lc.prereleaseIdentifier)
+
+	entry.WithField("tag", newVersion.String()).Info("incrementing to new version")
+
+	resolvedVersion, err := lc.createTag(newVersion.String(), commitSha)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Summary{
+		Tag:      resolvedVersion.String(),
+		Previous: lc.version.String(),
+		Bump:     string(incrementType),
+		Commit:   commitSha,
+	}, nil
+}
+
+// resolvePullRequestNumber looks up the pull request behind commit, first by
+// matching it against a refs/pull/*/head ref (present when the clone fetched
+// GitHub's pull ref namespace), then by parsing the default merge commit
+// message.
+func (lc *LocalClient) resolvePullRequestNumber(commit *object.Commit) string {
+	refs, err := lc.repo.References()
+	if err == nil {
+		prNumber := ""
+
+		_ = refs.ForEach(func(ref *plumbing.Reference) error {
+			name := ref.Name().String()
+			if strings.HasPrefix(name, "refs/pull/") && strings.HasSuffix(name, "/head") && ref.Hash() == commit.Hash {
+				prNumber = strings.TrimSuffix(strings.TrimPrefix(name, "refs/pull/"), "/head")
+
+				return storer.ErrStop
+			}
+
+			return nil
+		})
+
+		if prNumber != "" {
+			return prNumber
+		}
+	}
+
+	matches := mergeCommitPRRegExp.FindStringSubmatch(commit.Message)
+	if matches == nil {
+		return ""
+	}
+
+	return matches[1]
+}
+
+// commitMessagesSince returns the messages of every commit reachable from
+// newHash down to, but excluding, previousHash.
+func (lc *LocalClient) commitMessagesSince(previousHash string, newHash string) ([]string, error) {
+	excluded := map[plumbing.Hash]bool{}
+	if previousHash != "" {
+		previousIter, err := lc.repo.Log(&git.LogOptions{From: plumbing.NewHash(previousHash)})
+		if err == nil {
+			err = previousIter.ForEach(func(c *object.Commit) error { excluded[c.Hash] = true; return nil })
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk commit log: %v", err)
+		}
+	}
+
+	commitIter, err := lc.repo.Log(&git.LogOptions{From: plumbing.NewHash(newHash)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk commit log: %v", err)
+	}
+
+	var messages []string
+	err = commitIter.ForEach(func(c *object.Commit) error {
+		if excluded[c.Hash] {
+			return nil
+		}
+
+		messages = append(messages, c.Message)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// localPushAuth builds push credentials from the environment: a
+// GITHUB_TOKEN for HTTPS remotes, or an SSH_PRIVATE_KEY_PATH for SSH
+// remotes. Neither being set falls back to go-git's default SSH agent
+// handling.
+func localPushAuth() (transport.AuthMethod, error) {
+	if token, ok := os.LookupEnv("GITHUB_TOKEN"); ok && token != "" {
+		return &http.BasicAuth{Username: "x-access-token", Password: token}, nil
+	}
+
+	if keyPath, ok := os.LookupEnv("SSH_PRIVATE_KEY_PATH"); ok && keyPath != "" {
+		auth, err := ssh.NewPublicKeysFromFile("git", keyPath, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load SSH key from %s: %v", keyPath, err)
+		}
+
+		return auth, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,316 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infobloxopen/auto-semver-tag/pkg/release"
+	"github.com/infobloxopen/auto-semver-tag/pkg/semver"
+)
+
+type Repository struct {
+	releaseBranch string
+	version       semver.SemVer
+	versionHash   string
+}
+
+// RemoteClient drives the auto-semver-tag workflow against any SCM
+// implementation (GitHub, Gitea, GitLab, ...), reacting to a merged
+// pull/merge request event by tagging and, optionally, publishing release
+// notes.
+type RemoteClient struct {
+	scm                  SCM
+	repo                 Repository
+	source               IncrementSource
+	prereleaseIdentifier string
+	releaseNotes         bool
+	releaseNotesTemplate release.Template
+	allowIncompatible    bool
+}
+
+var _ Client = (*RemoteClient)(nil)
+
+// New builds a RemoteClient backed by the GitHub API.
+func New(token string, repository string, releaseBranch string, source IncrementSource, prereleaseIdentifier string,
+	releaseNotes bool, releaseNotesTemplatePath string, allowIncompatible bool) (*RemoteClient, error) {
+	parts := strings.Split(repository, "/")
+	owner := parts[0]
+	repoName := parts[1]
+
+	return NewWithSCM(NewGithubClient(token, owner, repoName), releaseBranch, source, prereleaseIdentifier,
+		releaseNotes, releaseNotesTemplatePath, allowIncompatible)
+}
+
+// NewWithSCM builds a RemoteClient backed by any SCM implementation, which
+// is how the gitea and gitlab packages plug into the same workflow as
+// GitHub.
+func NewWithSCM(scm SCM, releaseBranch string, source IncrementSource, prereleaseIdentifier string,
+	releaseNotes bool, releaseNotesTemplatePath string, allowIncompatible bool) (*RemoteClient, error) {
+	tags, err := scm.ListTags(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	version, commit := latestTag(tags)
+
+	log.WithFields(log.Fields{"tag": version.String(), "commit": commit}).Info("found previous version tag")
+
+	releaseNotesTemplate := release.DefaultTemplate
+	if releaseNotesTemplatePath != "" {
+		releaseNotesTemplate, err = release.LoadTemplate(releaseNotesTemplatePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &RemoteClient{
+		scm: scm,
+		repo: Repository{
+			releaseBranch: releaseBranch,
+			version:       version,
+			versionHash:   commit,
+		},
+		source:               source,
+		prereleaseIdentifier: prereleaseIdentifier,
+		releaseNotes:         releaseNotes,
+		releaseNotesTemplate: releaseNotesTemplate,
+		allowIncompatible:    allowIncompatible,
+	}, nil
+}
+
+func latestTag(tags map[string]string) (semver.SemVer, string) {
+	res := semver.SemVer{}
+	commit := ""
+
+	for name, sha := range tags {
+		version, err := semver.New(name)
+		if err != nil {
+			log.WithField("tag", name).Debug("ignoring non-semver tag")
+			continue
+		}
+
+		if version.IsGreaterThan(res) {
+			res = version
+			commit = sha
+		}
+	}
+
+	return res, commit
+}
+
+func (rc *RemoteClient) getLatestTag() (semver.SemVer, string, error) {
+	return rc.repo.version, rc.repo.versionHash, nil
+}
+
+// createTag creates the tag for version, resolved against the go.mod at
+// commitSha to guard against a major-version mismatch (see
+// resolveTagVersion). The returned SemVer is the one actually tagged, which
+// may differ from version when it was marked +incompatible.
+func (rc *RemoteClient) createTag(version string, commitSha string) (semver.SemVer, error) {
+	ctx := context.Background()
+
+	parsed, err := semver.New(version)
+	if err != nil {
+		return semver.SemVer{}, err
+	}
+
+	goModContents, goModOK, err := rc.scm.ReadFile(ctx, commitSha, "go.mod")
+	if err != nil {
+		return semver.SemVer{}, err
+	}
+
+	resolved, err := resolveTagVersion(parsed, goModContents, goModOK, rc.allowIncompatible)
+	if err != nil {
+		return semver.SemVer{}, err
+	}
+
+	if err := rc.scm.CreateTag(ctx, resolved.String(), commitSha); err != nil {
+		return semver.SemVer{}, err
+	}
+
+	return resolved, nil
+}
+
+func (rc *RemoteClient) PerformAction(commitSha string, eventDataFilePath string) (*Summary, error) {
+	entry := log.WithField("commit", commitSha)
+	entry.Debug("extracting event data")
+
+	event, err := rc.scm.ParseEvent(eventDataFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	pr := event.PullRequest
+	if pr == nil {
+		return nil, fmt.Errorf("pull request not found in data file")
+	}
+
+	entry = entry.WithField("pr", pr.Number)
+	entry.WithFields(log.Fields{
+		"action":      event.Action,
+		"merged":      pr.Merged,
+		"base":        pr.BaseBranch,
+		"mergeCommit": pr.MergeCommit,
+	}).Debug("evaluating pull request event")
+
+	if event.Action != "closed" {
+		return nil, fmt.Errorf("pull request is not closed: %s", event.Action)
+	}
+
+	if !pr.Merged {
+		return nil, fmt.Errorf("pull request is not merged")
+	}
+
+	if pr.BaseBranch != rc.repo.releaseBranch {
+		return nil, fmt.Errorf("pull request merged into a different branch (expected: %s, actual: %s)",
+			rc.repo.releaseBranch, pr.BaseBranch)
+	}
+
+	if pr.MergeCommit != commitSha {
+		return nil, fmt.Errorf("workflow run arguments and pull request data mismatch")
+	}
+
+	if pr.MergeCommit == rc.repo.versionHash {
+		entry.Info("commit is already tagged with the latest version, no new tag necessary")
+
+		return nil, nil
+	}
+
+	ctx := context.Background()
+
+	entry.WithField("source", rc.source).Debug("determining SemVer increment")
+
+	incrementType, err := rc.determineIncrementType(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	if incrementType == semver.IncrementTypeUnknown {
+		entry.WithField("tag", rc.repo.version.String()).Info("no SemVer labels or Conventional Commits found, keeping current version")
+
+		return nil, nil
+	}
+
+	entry = entry.WithField("increment", incrementType)
+	entry.Info("determined increment type")
+
+	newVersion := rc.repo.version.IncrementVersion(incrementType, rc.prereleaseIdentifier)
+
+	entry.WithField("tag", newVersion.String()).Info("incrementing to new version")
+
+	resolvedVersion, err := rc.createTag(newVersion.String(), commitSha)
+	if err != nil {
+		return nil, err
+	}
+
+	if rc.releaseNotes {
+		err = rc.publishReleaseNotes(ctx, resolvedVersion.String(), commitSha)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Summary{
+		Tag:      resolvedVersion.String(),
+		Previous: rc.repo.version.String(),
+		Bump:     string(incrementType),
+		Commit:   commitSha,
+	}, nil
+}
+
+// determineIncrementType resolves the SemVer increment type for the merged
+// pull/merge request according to rc.source. When both sources are enabled,
+// a SemVer label always overrides whatever the commit messages imply.
+func (rc *RemoteClient) determineIncrementType(ctx context.Context, pr *PullRequest) (semver.IncrementType, error) {
+	if rc.source == IncrementSourceLabels || rc.source == IncrementSourceBoth {
+		if labelType := parsePullRequestLabels(pr.Labels); labelType != semver.IncrementTypeUnknown {
+			return labelType, nil
+		}
+
+		if rc.source == IncrementSourceLabels {
+			return semver.IncrementTypeUnknown, nil
+		}
+	}
+
+	category, err := ClassifyPullRequestCommits(ctx, rc.scm, pr.Number)
+	if err != nil {
+		return semver.IncrementTypeUnknown, err
+	}
+
+	return categoryToIncrementType(category), nil
+}
+
+// publishReleaseNotes generates a Markdown release body from every
+// pull/merge request merged into the release branch since the previous tag
+// and publishes it as the release for newTag.
+func (rc *RemoteClient) publishReleaseNotes(ctx context.Context, newTag string, commitSha string) error {
+	labels := map[int][]string{}
+	classify := func(ctx context.Context, pr release.PullRequest) (release.Category, error) {
+		if rc.source != IncrementSourceCommits {
+			switch parsePullRequestLabels(labels[pr.Number]) {
+			case semver.IncrementTypeMajor:
+				return release.CategoryBreaking, nil
+			case semver.IncrementTypeMinor:
+				return release.CategoryFeature, nil
+			case semver.IncrementTypePatch:
+				return release.CategoryFix, nil
+			}
+			if rc.source == IncrementSourceLabels {
+				return release.CategoryOther, nil
+			}
+		}
+
+		return ClassifyPullRequestCommits(ctx, rc.scm, pr.Number)
+	}
+
+	listMerged := func(ctx context.Context, releaseBranch string, previousCommit string, newCommit string) ([]release.PullRequest, error) {
+		prs, err := rc.scm.ListMergedPullRequests(ctx, releaseBranch, previousCommit, newCommit)
+		if err != nil {
+			return nil, err
+		}
+
+		entries := make([]release.PullRequest, 0, len(prs))
+		for _, pr := range prs {
+			labels[pr.Number] = pr.Labels
+			entries = append(entries, release.PullRequest{Number: pr.Number, Title: pr.Title, Author: pr.Author})
+		}
+
+		return entries, nil
+	}
+
+	previousTag := ""
+	compareURL := ""
+	if rc.repo.versionHash != "" {
+		previousTag = rc.repo.version.String()
+		compareURL = rc.scm.CompareURL(previousTag, newTag)
+	}
+
+	body, err := release.Generate(ctx, rc.repo.releaseBranch, previousTag, newTag, rc.repo.versionHash, commitSha,
+		compareURL, listMerged, classify, rc.releaseNotesTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to generate release notes: %v", err)
+	}
+
+	log.WithField("tag", newTag).Info("publishing release notes")
+
+	return rc.scm.CreateRelease(ctx, newTag, body)
+}
+
+// parsePullRequestLabels returns the SemVer increment implied by labels,
+// including "promote" to finalize the current prerelease (see
+// semver.IncrementTypePromote), or IncrementTypeUnknown when none match.
+func parsePullRequestLabels(labels []string) semver.IncrementType {
+	incType := semver.IncrementTypeUnknown
+	for _, label := range labels {
+		t := semver.StringToIncrementType(label)
+
+		if t < incType {
+			incType = t
+		}
+	}
+
+	return incType
+}
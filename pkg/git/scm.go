@@ -0,0 +1,60 @@
+package git
+
+import "context"
+
+// PullRequest is a provider-agnostic view of a merged pull/merge request.
+// GithubClient and the sibling gitea and gitlab packages each map their own
+// API types onto it.
+type PullRequest struct {
+	Number      int
+	Title       string
+	Author      string
+	Labels      []string
+	BaseBranch  string
+	MergeCommit string
+	Merged      bool
+}
+
+// PullRequestEvent is a provider-agnostic view of the webhook/event payload
+// that triggers PerformAction: a pull/merge request being closed.
+type PullRequestEvent struct {
+	Action      string
+	PullRequest *PullRequest
+}
+
+// SCM is the provider-agnostic surface auto-semver-tag needs from a source
+// control host. GithubClient (in this package) and the sibling gitea and
+// gitlab packages each implement it, so RemoteClient can drive the same
+// workflow regardless of which one it is talking to.
+type SCM interface {
+	// ListTags returns the commit SHA for every ref under refs/tags.
+	ListTags(ctx context.Context) (map[string]string, error)
+
+	// ListPullRequestCommitMessages returns the raw commit messages making up
+	// a pull/merge request, for Conventional Commits parsing.
+	ListPullRequestCommitMessages(ctx context.Context, prNumber int) ([]string, error)
+
+	// ListMergedPullRequests returns every pull/merge request merged into
+	// baseBranch whose merge commit lies between previousCommit and
+	// newCommit.
+	ListMergedPullRequests(ctx context.Context, baseBranch string, previousCommit string, newCommit string) ([]*PullRequest, error)
+
+	// ParseEvent parses the provider's webhook/event payload file into a
+	// provider-agnostic PullRequestEvent.
+	ParseEvent(eventFilePath string) (*PullRequestEvent, error)
+
+	// CreateTag creates and publishes tagName pointing at commitSHA.
+	CreateTag(ctx context.Context, tagName string, commitSHA string) error
+
+	// CreateRelease publishes a release named tagName with the given body.
+	CreateRelease(ctx context.Context, tagName string, body string) error
+
+	// CompareURL returns a human-facing link comparing previousTag and
+	// newTag, used as the release notes' changelog header.
+	CompareURL(previousTag string, newTag string) string
+
+	// ReadFile returns the contents of path as of ref (a commit SHA or
+	// branch name). ok is false, with a nil error, when path does not exist
+	// at ref.
+	ReadFile(ctx context.Context, ref string, path string) (contents string, ok bool, err error)
+}
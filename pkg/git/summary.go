@@ -0,0 +1,11 @@
+package git
+
+// Summary is the machine-parseable result of a successful PerformAction,
+// printed as its final JSON event so downstream workflow steps can consume
+// it (e.g. via jq) instead of scraping human-readable log lines.
+type Summary struct {
+	Tag      string `json:"tag"`
+	Previous string `json:"previous"`
+	Bump     string `json:"bump"`
+	Commit   string `json:"commit"`
+}
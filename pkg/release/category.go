@@ -0,0 +1,34 @@
+package release
+
+// Category buckets a merged pull request into a release notes section. The
+// zero value, "", is not one of the categories below; unclassified pull
+// requests use CategoryOther.
+type Category string
+
+const (
+	CategoryBreaking Category = "breaking"
+	CategoryFeature  Category = "feature"
+	CategoryFix      Category = "fix"
+	CategoryDocs     Category = "docs"
+	CategoryOther    Category = "other"
+)
+
+// precedence orders categories from most to least significant so that, when
+// a pull request contains commits of more than one kind, the single most
+// important one decides which section it lands in.
+var precedence = map[Category]int{
+	CategoryBreaking: 0,
+	CategoryFeature:  1,
+	CategoryFix:      2,
+	CategoryDocs:     3,
+	CategoryOther:    4,
+}
+
+// Highest returns whichever of a and b is the more significant category.
+func Highest(a Category, b Category) Category {
+	if precedence[a] <= precedence[b] {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,69 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// PullRequest is the minimal view of a merged pull/merge request release
+// notes need: enough to render one line, regardless of which SCM it came
+// from.
+type PullRequest struct {
+	Number int
+	Title  string
+	Author string
+}
+
+// ListMergedPullRequests returns every pull request merged into
+// releaseBranch whose merge commit lies between previousCommit and
+// newCommit.
+type ListMergedPullRequests func(ctx context.Context, releaseBranch string, previousCommit string, newCommit string) ([]PullRequest, error)
+
+// Classifier determines which Category a merged pull request belongs to,
+// using whatever signal also decided its version bump.
+type Classifier func(ctx context.Context, pr PullRequest) (Category, error)
+
+// Generate walks every pull request merged into releaseBranch between
+// previousCommit and newCommit, classifies each with classify, and renders
+// the grouped Markdown body for a release, optionally prefixed with a
+// compare link back to the previous tag.
+func Generate(ctx context.Context, releaseBranch string, previousTag string, newTag string, previousCommit string, newCommit string,
+	compareURL string, listMerged ListMergedPullRequests, classify Classifier, tmpl Template) (string, error) {
+
+	prs, err := listMerged(ctx, releaseBranch, previousCommit, newCommit)
+	if err != nil {
+		return "", err
+	}
+
+	grouped := map[Category][]PullRequest{}
+	for _, pr := range prs {
+		category, err := classify(ctx, pr)
+		if err != nil {
+			return "", err
+		}
+
+		grouped[category] = append(grouped[category], pr)
+	}
+
+	var b strings.Builder
+
+	if previousTag != "" && compareURL != "" {
+		fmt.Fprintf(&b, "**Full Changelog**: %s\n\n", compareURL)
+	}
+
+	for _, section := range tmpl {
+		entries := grouped[section.Category]
+		if len(entries) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, "## %s\n", section.Heading)
+		for _, pr := range entries {
+			fmt.Fprintf(&b, "- %s (#%d, @%s)\n", pr.Title, pr.Number, pr.Author)
+		}
+		b.WriteString("\n")
+	}
+
+	return strings.TrimRight(b.String(), "\n") + "\n", nil
+}
@@ -0,0 +1,49 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// SectionTemplate names one release notes section and the heading it should
+// render under.
+type SectionTemplate struct {
+	Category Category `json:"category"`
+	Heading  string   `json:"heading"`
+}
+
+// Template controls which categories are rendered, the heading used for
+// each, and the order sections appear in. Categories absent from a Template
+// are omitted from the generated notes entirely.
+type Template []SectionTemplate
+
+// DefaultTemplate mirrors the conventional GitHub-style release notes
+// layout used when --release-notes-template is not set.
+var DefaultTemplate = Template{
+	{Category: CategoryBreaking, Heading: "🚨 Breaking Changes"},
+	{Category: CategoryFeature, Heading: "✨ Features"},
+	{Category: CategoryFix, Heading: "🐛 Bug Fixes"},
+	{Category: CategoryDocs, Heading: "📖 Docs"},
+	{Category: CategoryOther, Heading: "🌱 Others"},
+}
+
+// LoadTemplate reads a JSON-encoded Template from path, e.g.:
+//
+//	[
+//	  {"category": "breaking", "heading": "Breaking Changes"},
+//	  {"category": "feature", "heading": "New Features"}
+//	]
+func LoadTemplate(path string) (Template, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read release notes template %s: %v", path, err)
+	}
+
+	var tmpl Template
+	if err := json.Unmarshal(data, &tmpl); err != nil {
+		return nil, fmt.Errorf("failed to parse release notes template %s: %v", path, err)
+	}
+
+	return tmpl, nil
+}
@@ -7,7 +7,7 @@ import (
 	"strings"
 )
 
-// IncrementType allow alphanumeric comparison: major < minor < patch < unknown
+// IncrementType allows alphanumeric comparison: major < minor < patch < prerelease < promote < unknown
 type IncrementType string
 
 const (
@@ -17,12 +17,16 @@ const (
 		`(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
 		`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`
 
-	IncrementTypeMajor   IncrementType = "major"
-	IncrementTypeMinor   IncrementType = "minor"
-	IncrementTypePatch   IncrementType = "patch"
-	IncrementTypeUnknown IncrementType = "unknown"
+	IncrementTypeMajor      IncrementType = "major"
+	IncrementTypeMinor      IncrementType = "minor"
+	IncrementTypePatch      IncrementType = "patch"
+	IncrementTypePrerelease IncrementType = "prerelease"
+	IncrementTypePromote    IncrementType = "promote"
+	IncrementTypeUnknown    IncrementType = "unknown"
 )
 
+var semVerRegExp = regexp.MustCompile(SemVerRegExp)
+
 func StringToIncrementType(val string) IncrementType {
 	switch val {
 	case string(IncrementTypeMajor):
@@ -31,15 +35,24 @@ func StringToIncrementType(val string) IncrementType {
 		return IncrementTypeMinor
 	case string(IncrementTypePatch):
 		return IncrementTypePatch
+	case string(IncrementTypePrerelease):
+		return IncrementTypePrerelease
+	case string(IncrementTypePromote):
+		return IncrementTypePromote
 	default:
 		return IncrementTypeUnknown
 	}
 }
 
+// SemVer holds a parsed SemVer 2.0.0 version. Prerelease and Build are kept
+// as their raw dot-separated text (e.g. "rc.1") since that is how both
+// precedence comparison and rendering need to work with them.
 type SemVer struct {
-	major uint64
-	minor uint64
-	patch uint64
+	major      uint64
+	minor      uint64
+	patch      uint64
+	prerelease string
+	build      string
 }
 
 func newInvalidSemVerError(semVer string) (SemVer, error) {
@@ -47,53 +60,43 @@ func newInvalidSemVerError(semVer string) (SemVer, error) {
 }
 
 func New(semVer string) (SemVer, error) {
-
-	isSemVerValid, err := regexp.MatchString(SemVerRegExp, semVer)
-	if err != nil || !isSemVerValid {
+	matches := semVerRegExp.FindStringSubmatch(semVer)
+	if matches == nil {
 		return newInvalidSemVerError(semVer)
 	}
 
-	if strings.Index(semVer, "v") == 0 {
-		semVer = semVer[1:]
-	}
-
-	parts := strings.SplitN(semVer, ".", 3)
-
-	major, err := strconv.ParseUint(parts[0], 10, 64)
+	major, err := strconv.ParseUint(matches[1], 10, 64)
 	if err != nil {
 		return newInvalidSemVerError(semVer)
 	}
 
-	minor, err := strconv.ParseUint(parts[1], 10, 64)
+	minor, err := strconv.ParseUint(matches[2], 10, 64)
 	if err != nil {
 		return newInvalidSemVerError(semVer)
 	}
 
-	patchStr := parts[2]
-
-	if buildIndex := strings.IndexRune(patchStr, '+'); buildIndex != -1 {
-		patchStr = patchStr[:buildIndex]
-	}
-
-	if preIndex := strings.IndexRune(patchStr, '-'); preIndex != -1 {
-		patchStr = patchStr[:preIndex]
-	}
-
-	patch, err := strconv.ParseUint(patchStr, 10, 64)
+	patch, err := strconv.ParseUint(matches[3], 10, 64)
 	if err != nil {
 		return newInvalidSemVerError(semVer)
 	}
 
 	res := SemVer{
-		major,
-		minor,
-		patch,
+		major:      major,
+		minor:      minor,
+		patch:      patch,
+		prerelease: matches[4],
+		build:      matches[5],
 	}
 
-	return res, err
+	return res, nil
 }
 
-func (s SemVer) IncrementVersion(incrementType IncrementType) SemVer {
+// IncrementVersion bumps s according to incrementType. For
+// IncrementTypePrerelease, prereleaseIdentifier (e.g. "rc") is used to start
+// or continue a prerelease series; it is ignored for every other type.
+// IncrementTypePromote finalizes the current prerelease instead of bumping
+// anything further; see Promote.
+func (s SemVer) IncrementVersion(incrementType IncrementType, prereleaseIdentifier string) SemVer {
 	switch incrementType {
 	case IncrementTypeMajor:
 		return SemVer{
@@ -114,36 +117,160 @@ func (s SemVer) IncrementVersion(incrementType IncrementType) SemVer {
 			minor: s.minor,
 			patch: s.patch + 1,
 		}
+	case IncrementTypePrerelease:
+		return s.incrementPrerelease(prereleaseIdentifier)
+	case IncrementTypePromote:
+		return s.Promote()
 	default:
 		panic("invalid increment type")
 	}
 }
 
+// incrementPrerelease continues the current prerelease series (e.g.
+// "rc.1" -> "rc.2") when prereleaseIdentifier matches it, or otherwise cuts
+// a new prerelease ("rc.1") on top of the next patch version.
+func (s SemVer) incrementPrerelease(prereleaseIdentifier string) SemVer {
+	if identifier, number, ok := splitPrerelease(s.prerelease); ok && identifier == prereleaseIdentifier {
+		return SemVer{
+			major:      s.major,
+			minor:      s.minor,
+			patch:      s.patch,
+			prerelease: fmt.Sprintf("%s.%d", prereleaseIdentifier, number+1),
+		}
+	}
+
+	return SemVer{
+		major:      s.major,
+		minor:      s.minor,
+		patch:      s.patch + 1,
+		prerelease: fmt.Sprintf("%s.1", prereleaseIdentifier),
+	}
+}
+
+// Promote drops the prerelease and build metadata, turning e.g.
+// "v1.2.3-rc.2" into the final release "v1.2.3".
+func (s SemVer) Promote() SemVer {
+	return SemVer{
+		major: s.major,
+		minor: s.minor,
+		patch: s.patch,
+	}
+}
+
+// Major returns the version's major component.
+func (s SemVer) Major() uint64 {
+	return s.major
+}
+
+// WithBuild returns a copy of s carrying the given build metadata, e.g. to
+// mark a tag "+incompatible".
+func (s SemVer) WithBuild(build string) SemVer {
+	s.build = build
+	return s
+}
+
+func splitPrerelease(prerelease string) (identifier string, number uint64, ok bool) {
+	idx := strings.LastIndex(prerelease, ".")
+	if idx == -1 {
+		return "", 0, false
+	}
+
+	number, err := strconv.ParseUint(prerelease[idx+1:], 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+
+	return prerelease[:idx], number, true
+}
+
 func (s SemVer) String() string {
-	return fmt.Sprintf("v%d.%d.%d", s.major, s.minor, s.patch)
+	str := fmt.Sprintf("v%d.%d.%d", s.major, s.minor, s.patch)
+
+	if s.prerelease != "" {
+		str += "-" + s.prerelease
+	}
+
+	if s.build != "" {
+		str += "+" + s.build
+	}
+
+	return str
 }
 
+// IsGreaterThan implements SemVer 2.0.0 precedence: core versions are
+// compared numerically; build metadata is ignored entirely; a prerelease
+// version has lower precedence than the same core version without one; and
+// two prereleases are compared identifier-by-identifier.
 func (s SemVer) IsGreaterThan(o SemVer) bool {
-	if s.major > o.major {
-		return true
+	if s.major != o.major {
+		return s.major > o.major
 	}
-	if s.major < o.major {
-		return false
+	if s.minor != o.minor {
+		return s.minor > o.minor
+	}
+	if s.patch != o.patch {
+		return s.patch > o.patch
 	}
 
-	// Major versions are equal
-
-	if s.minor > o.minor {
+	if s.prerelease == "" && o.prerelease == "" {
+		return false
+	}
+	if s.prerelease == "" {
 		return true
 	}
-	if s.minor < o.minor {
+	if o.prerelease == "" {
 		return false
 	}
 
-	// Major and minor versions are equal
+	return comparePrereleases(s.prerelease, o.prerelease) > 0
+}
 
-	if s.patch > o.patch {
-		return true
+// comparePrereleases compares two dot-separated prerelease strings per
+// SemVer 2.0.0 rule 11: identifiers are compared left to right, numeric
+// identifiers are compared numerically and always have lower precedence
+// than alphanumeric ones, and a larger set of identifiers wins when all
+// preceding identifiers are equal.
+func comparePrereleases(a string, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		if c := compareIdentifiers(aParts[i], bParts[i]); c != 0 {
+			return c
+		}
+	}
+
+	return len(aParts) - len(bParts)
+}
+
+func compareIdentifiers(a string, b string) int {
+	aNum, aIsNum := parseUint(a)
+	bNum, bIsNum := parseUint(b)
+
+	switch {
+	case aIsNum && bIsNum:
+		switch {
+		case aNum < bNum:
+			return -1
+		case aNum > bNum:
+			return 1
+		default:
+			return 0
+		}
+	case aIsNum:
+		return -1
+	case bIsNum:
+		return 1
+	default:
+		return strings.Compare(a, b)
 	}
-	return false
+}
+
+func parseUint(s string) (uint64, bool) {
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return n, true
 }
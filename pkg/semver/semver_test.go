@@ -17,37 +17,43 @@ func TestSemverParsing(t *testing.T) {
 		{
 			name:          "Valid version with 'v': v1.0.0",
 			strToParse:    "v1.0.0",
-			expectedRes:   SemVer{1, 0, 0},
+			expectedRes:   SemVer{major: 1, minor: 0, patch: 0},
 			expectedError: nil,
 		},
 		{
 			name:          "Valid version without 'v': 1.0.0",
 			strToParse:    "1.0.0",
-			expectedRes:   SemVer{1, 0, 0},
+			expectedRes:   SemVer{major: 1, minor: 0, patch: 0},
 			expectedError: nil,
 		},
 		{
 			name:          "Not valid version with leading '0': 01.0.0",
 			strToParse:    "01.0.0",
-			expectedRes:   SemVer{0, 0, 0},
+			expectedRes:   SemVer{},
 			expectedError: fmt.Errorf("invalid semver: %s", "01.0.0"),
 		},
 		{
 			name:          "Valid version with prerelease: 1.0.1-rc.1",
 			strToParse:    "v1.0.1-rc.1",
-			expectedRes:   SemVer{1, 0, 1},
+			expectedRes:   SemVer{major: 1, minor: 0, patch: 1, prerelease: "rc.1"},
 			expectedError: nil,
 		},
 		{
 			name:          "Valid version with build: 1.0.1+build.1",
 			strToParse:    "1.0.1+build.1",
-			expectedRes:   SemVer{1, 0, 1},
+			expectedRes:   SemVer{major: 1, minor: 0, patch: 1, build: "build.1"},
+			expectedError: nil,
+		},
+		{
+			name:          "Valid version with prerelease and build: 1.0.1-rc.1+build.1",
+			strToParse:    "v1.0.1-rc.1+build.1",
+			expectedRes:   SemVer{major: 1, minor: 0, patch: 1, prerelease: "rc.1", build: "build.1"},
 			expectedError: nil,
 		},
 		{
 			name:          "Valid version with multi-digits: v12.34.56",
 			strToParse:    "v12.34.56",
-			expectedRes:   SemVer{12, 34, 56},
+			expectedRes:   SemVer{major: 12, minor: 34, patch: 56},
 			expectedError: nil,
 		},
 	}
@@ -71,55 +77,139 @@ func TestSemverParsing(t *testing.T) {
 }
 
 func TestSemverIncrement(t *testing.T) {
-	v567 := SemVer{5, 6, 7}
+	v567 := SemVer{major: 5, minor: 6, patch: 7}
 
 	tests := []struct {
-		before    SemVer
-		after     SemVer
-		increment IncrementType
+		before               SemVer
+		after                SemVer
+		increment            IncrementType
+		prereleaseIdentifier string
 	}{
 		{
 			before:    SemVer{},
-			after:     SemVer{1, 0, 0},
+			after:     SemVer{major: 1, minor: 0, patch: 0},
 			increment: IncrementTypeMajor,
 		},
 		{
 			before:    SemVer{},
-			after:     SemVer{0, 1, 0},
+			after:     SemVer{major: 0, minor: 1, patch: 0},
 			increment: IncrementTypeMinor,
 		},
 		{
 			before:    SemVer{},
-			after:     SemVer{0, 0, 1},
+			after:     SemVer{major: 0, minor: 0, patch: 1},
 			increment: IncrementTypePatch,
 		},
 
 		{
 			before:    v567,
-			after:     SemVer{6, 0, 0},
+			after:     SemVer{major: 6, minor: 0, patch: 0},
 			increment: IncrementTypeMajor,
 		},
 		{
 			before:    v567,
-			after:     SemVer{5, 7, 0},
+			after:     SemVer{major: 5, minor: 7, patch: 0},
 			increment: IncrementTypeMinor,
 		},
 		{
 			before:    v567,
-			after:     SemVer{5, 6, 8},
+			after:     SemVer{major: 5, minor: 6, patch: 8},
 			increment: IncrementTypePatch,
 		},
+		{
+			before:               v567,
+			after:                SemVer{major: 5, minor: 6, patch: 8, prerelease: "rc.1"},
+			increment:            IncrementTypePrerelease,
+			prereleaseIdentifier: "rc",
+		},
+		{
+			before:               SemVer{major: 1, minor: 2, patch: 3, prerelease: "rc.1"},
+			after:                SemVer{major: 1, minor: 2, patch: 3, prerelease: "rc.2"},
+			increment:            IncrementTypePrerelease,
+			prereleaseIdentifier: "rc",
+		},
+		{
+			before:               SemVer{major: 1, minor: 2, patch: 3, prerelease: "rc.1"},
+			after:                SemVer{major: 1, minor: 2, patch: 4, prerelease: "beta.1"},
+			increment:            IncrementTypePrerelease,
+			prereleaseIdentifier: "beta",
+		},
+		{
+			before:    SemVer{major: 1, minor: 2, patch: 3, prerelease: "rc.2", build: "build.1"},
+			after:     SemVer{major: 1, minor: 2, patch: 3},
+			increment: IncrementTypePromote,
+		},
 	}
 
 	for _, tt := range tests {
 		name := fmt.Sprintf("Increment %s from %s to %s", tt.increment, tt.before, tt.after)
 		t.Run(name, func(t *testing.T) {
-			res := tt.before.IncrementVersion(tt.increment)
+			res := tt.before.IncrementVersion(tt.increment, tt.prereleaseIdentifier)
 			assert.Equal(t, tt.after.String(), res.String())
 		})
 	}
 }
 
+func TestSemverPromote(t *testing.T) {
+	res := SemVer{major: 1, minor: 2, patch: 3, prerelease: "rc.2", build: "build.1"}.Promote()
+	assert.Equal(t, "v1.2.3", res.String())
+}
+
+func TestSemverMajor(t *testing.T) {
+	assert.Equal(t, uint64(2), SemVer{major: 2, minor: 3, patch: 4}.Major())
+}
+
+func TestSemverWithBuild(t *testing.T) {
+	res := SemVer{major: 2, minor: 0, patch: 0}.WithBuild("incompatible")
+	assert.Equal(t, "v2.0.0+incompatible", res.String())
+}
+
+func TestSemverIsGreaterThan(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        SemVer
+		b        SemVer
+		expected bool
+	}{
+		{
+			name:     "release is greater than prerelease of same core",
+			a:        SemVer{major: 1, minor: 0, patch: 0},
+			b:        SemVer{major: 1, minor: 0, patch: 0, prerelease: "rc.1"},
+			expected: true,
+		},
+		{
+			name:     "prerelease is not greater than release of same core",
+			a:        SemVer{major: 1, minor: 0, patch: 0, prerelease: "rc.1"},
+			b:        SemVer{major: 1, minor: 0, patch: 0},
+			expected: false,
+		},
+		{
+			name:     "higher prerelease number is greater",
+			a:        SemVer{major: 1, minor: 0, patch: 0, prerelease: "rc.2"},
+			b:        SemVer{major: 1, minor: 0, patch: 0, prerelease: "rc.1"},
+			expected: true,
+		},
+		{
+			name:     "alphanumeric identifier outranks numeric identifier",
+			a:        SemVer{major: 1, minor: 0, patch: 0, prerelease: "rc.alpha"},
+			b:        SemVer{major: 1, minor: 0, patch: 0, prerelease: "rc.1"},
+			expected: true,
+		},
+		{
+			name:     "build metadata is ignored",
+			a:        SemVer{major: 1, minor: 0, patch: 0, build: "build.2"},
+			b:        SemVer{major: 1, minor: 0, patch: 0, build: "build.1"},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.a.IsGreaterThan(tt.b))
+		})
+	}
+}
+
 func TestStringToIncrementType(t *testing.T) {
 	tests := []struct {
 		val  string
@@ -137,6 +227,10 @@ func TestStringToIncrementType(t *testing.T) {
 			val:  "patch",
 			want: IncrementTypePatch,
 		},
+		{
+			val:  "promote",
+			want: IncrementTypePromote,
+		},
 		{
 			val:  "Major",
 			want: IncrementTypeUnknown,